@@ -0,0 +1,253 @@
+// Package audio toca os áudios dos movimentos, buscando primeiro em um
+// sound pack do usuário em disco e caindo para o pack embutido no
+// binário quando uma amostra não existe no pack escolhido.
+//
+// As amostras são decodificadas uma única vez para um beep.Buffer e
+// tocadas através de um beep.Mixer compartilhado, para que uma
+// sequência de golpes não precise esperar o callback de término de
+// cada áudio antes de iniciar o próximo.
+package audio
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/effects"
+	"github.com/faiface/beep/mp3"
+	"github.com/faiface/beep/speaker"
+	"github.com/faiface/beep/vorbis"
+	"github.com/faiface/beep/wav"
+)
+
+// extensões suportadas, na ordem em que são tentadas em disco.
+var extDecoders = []struct {
+	ext    string
+	decode func(*os.File) (beep.StreamSeekCloser, beep.Format, error)
+}{
+	{".mp3", func(f *os.File) (beep.StreamSeekCloser, beep.Format, error) { return mp3.Decode(f) }},
+	{".wav", func(f *os.File) (beep.StreamSeekCloser, beep.Format, error) { return wav.Decode(f) }},
+	{".ogg", func(f *os.File) (beep.StreamSeekCloser, beep.Format, error) { return vorbis.Decode(f) }},
+}
+
+// PlayOptions ajusta como uma amostra individual é tocada.
+type PlayOptions struct {
+	// Speed é a razão de resample (1 = velocidade normal). 0 equivale a 1.
+	Speed float64
+	// Volume é o deslocamento em oitavas aplicado por effects.Volume
+	// (0 = inalterado, negativo = mais baixo, positivo = mais alto).
+	Volume float64
+}
+
+// SamplePlayer pré-carrega cada amostra usada no treino em um
+// beep.Buffer e as toca através de um único beep.Mixer registrado no
+// speaker, evitando redecodificar o mesmo arquivo a cada repetição.
+type SamplePlayer struct {
+	embedded embed.FS
+	diskDir  string // diretório do sound pack escolhido, "" = só o embutido
+
+	// audioName resolve o nome de um movimento para o nome de arquivo
+	// (sem extensão) de fato usado para achar a amostra, permitindo que
+	// um movepack aponte um movimento para uma amostra com nome
+	// diferente (MoveDef.Audio). nil equivale à identidade.
+	audioName func(name string) string
+
+	mixer *beep.Mixer
+
+	mu    sync.Mutex
+	cache map[string]*beep.Buffer
+}
+
+// NewSamplePlayer cria um SamplePlayer e registra seu mixer no
+// speaker. speaker.Init já deve ter sido chamado antes. audioName pode
+// ser nil, caso em que o nome do movimento é usado como nome de
+// arquivo diretamente.
+func NewSamplePlayer(embedded embed.FS, diskDir string, audioName func(name string) string) *SamplePlayer {
+	mixer := &beep.Mixer{}
+	speaker.Play(mixer)
+
+	return &SamplePlayer{
+		embedded:  embedded,
+		diskDir:   diskDir,
+		audioName: audioName,
+		mixer:     mixer,
+		cache:     map[string]*beep.Buffer{},
+	}
+}
+
+// Preload decodifica todas as amostras de `names` de uma vez, para que
+// o treino não precise decodificar nada em tempo real.
+func (p *SamplePlayer) Preload(names []string) error {
+	for _, name := range names {
+		if _, err := p.buffer(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Play toca a amostra `name` e bloqueia pela duração exata dela (mais
+// o ajuste de velocidade), em vez de esperar o callback de término do
+// beep. Para tocar uma série inteira sem atraso serial entre os
+// movimentos, veja PlaySequence.
+func (p *SamplePlayer) Play(name string) error {
+	_, err := p.PlayWithOptions(name, PlayOptions{})
+	return err
+}
+
+// PlayWithOptions é como Play, mas permite ajustar velocidade e volume
+// dessa amostra especificamente, retornando quanto tempo ela ocupou.
+func (p *SamplePlayer) PlayWithOptions(name string, opts PlayOptions) (time.Duration, error) {
+	buf, err := p.buffer(name)
+	if err != nil {
+		return 0, err
+	}
+
+	speed := opts.Speed
+	if speed == 0 {
+		speed = 1
+	}
+
+	var s beep.Streamer = buf.Streamer(0, buf.Len())
+	if speed != 1 {
+		s = beep.ResampleRatio(4, speed, s)
+	}
+	if opts.Volume != 0 {
+		s = &effects.Volume{Streamer: s, Base: 2, Volume: opts.Volume}
+	}
+
+	dur := buf.Format().SampleRate.D(buf.Len())
+	if speed != 1 {
+		dur = time.Duration(float64(dur) / speed)
+	}
+
+	p.mixer.Add(s)
+	time.Sleep(dur)
+
+	return dur, nil
+}
+
+// PlaySequence toca uma série de amostras em seguida, sem o atraso
+// serial de "toca, espera terminar, toca a próxima": os offsets de
+// cada amostra na linha do tempo são calculados de uma vez a partir da
+// duração de cada uma, e uma única goroutine agenda os beep.Mixer.Add
+// nesses offsets precisos, já com as amostras seguintes pré-carregadas
+// — isso também é o que possibilita, no futuro, avisos sobrepostos.
+// PlaySequence não bloqueia: retorna imediatamente a duração total
+// estimada da série, para quem chamou decidir se espera por ela.
+func (p *SamplePlayer) PlaySequence(names []string) (time.Duration, error) {
+	type scheduled struct {
+		at       time.Duration
+		streamer beep.Streamer
+	}
+
+	schedule := make([]scheduled, len(names))
+	total := time.Duration(0)
+
+	for i, name := range names {
+		buf, err := p.buffer(name)
+		if err != nil {
+			return 0, err
+		}
+
+		schedule[i] = scheduled{at: total, streamer: buf.Streamer(0, buf.Len())}
+		total += buf.Format().SampleRate.D(buf.Len())
+	}
+
+	go func() {
+		start := time.Now()
+		for _, sch := range schedule {
+			if delay := sch.at - time.Since(start); delay > 0 {
+				time.Sleep(delay)
+			}
+			p.mixer.Add(sch.streamer)
+		}
+	}()
+
+	return total, nil
+}
+
+func (p *SamplePlayer) buffer(name string) (*beep.Buffer, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if buf, ok := p.cache[name]; ok {
+		return buf, nil
+	}
+
+	streamer, format, err := p.decode(name)
+	if err != nil {
+		return nil, err
+	}
+	defer streamer.Close()
+
+	buf := beep.NewBuffer(format)
+	buf.Append(streamer)
+	p.cache[name] = buf
+
+	return buf, nil
+}
+
+func (p *SamplePlayer) decode(name string) (beep.StreamSeekCloser, beep.Format, error) {
+	file := name
+	if p.audioName != nil {
+		file = p.audioName(name)
+	}
+
+	if p.diskDir != "" {
+		for _, d := range extDecoders {
+			f, err := os.Open(filepath.Join(p.diskDir, file+d.ext))
+			if err != nil {
+				continue
+			}
+			return d.decode(f)
+		}
+	}
+
+	f, err := p.embedded.Open("sound/" + file + ".mp3")
+	if err != nil {
+		return nil, beep.Format{}, fmt.Errorf("audio: amostra %q não encontrada no sound pack nem no pack embutido", name)
+	}
+
+	return mp3.Decode(f)
+}
+
+// PacksDir retorna o diretório onde o manopla procura sound packs do
+// usuário: ~/.config/manopla/sounds.
+func PacksDir() (string, error) {
+	cfg, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cfg, "manopla", "sounds"), nil
+}
+
+// ListPacks lista os sound packs disponíveis em PacksDir, ou seja,
+// cada subdiretório dele.
+func ListPacks() ([]string, error) {
+	dir, err := PacksDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	packs := []string{}
+	for _, e := range entries {
+		if e.IsDir() {
+			packs = append(packs, e.Name())
+		}
+	}
+
+	return packs, nil
+}
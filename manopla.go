@@ -2,261 +2,174 @@ package main
 
 import (
 	"embed"
-	"flag"
 	"fmt"
 	"log"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"time"
 
+	"flag"
+
 	"github.com/faiface/beep"
-	"github.com/faiface/beep/mp3"
 	"github.com/faiface/beep/speaker"
+	"github.com/igoracmelo/manopla-go/audio"
+	"github.com/igoracmelo/manopla-go/movepack"
+	"github.com/igoracmelo/manopla-go/session"
+	"github.com/igoracmelo/manopla-go/training"
+	"github.com/igoracmelo/manopla-go/ui"
 )
 
-type move struct {
-	name  string
-	front bool
-	back  bool
-	leg   bool
-
-	// a probabilidade é um valor percentual inverso, para evitar
-	// de precisar preenchê-la para cada `move`.
-	// 0.0 significa que o movimento pode sempre aparecer, enquanto 1.0
-	// significa que o movimento nunca poderá aparecer
-	prob float64
-}
-
-type rule func(move) bool
-
-type globalOptions struct {
-	maxDistinct int
-	seqSize     int
-	onlyArm     bool
-	onlyLeg     bool
-	interval    time.Duration
-}
-
-type rulesOptions struct {
-	isFront       bool
-	previousMoves []move
-	seqSize       int
-}
-
 //go:embed sound
 var fs embed.FS
 
 func main() {
-	o := globalOptions{}
-	flag.IntVar(&o.maxDistinct, "d", 0, "Número de movimentos distintos. 0 permite todos os movimentos")
-	flag.IntVar(&o.seqSize, "n", 2, "Número de movimentos por série")
-	flag.BoolVar(&o.onlyLeg, "ol", false, "Permitir apenas golpes de perna")
-	flag.BoolVar(&o.onlyArm, "oa", false, "Permitir apenas golpes de braço")
-	flag.DurationVar(&o.interval, "t", 1*time.Second, "Intervalo entre as séries")
+	o := training.GlobalOptions{}
+	rc := training.RoundConfig{}
+	flag.IntVar(&o.MaxDistinct, "d", 0, "Número de movimentos distintos. 0 permite todos os movimentos")
+	flag.IntVar(&o.SeqSize, "n", 2, "Número de movimentos por série")
+	flag.BoolVar(&o.OnlyLeg, "ol", false, "Permitir apenas golpes de perna")
+	flag.BoolVar(&o.OnlyArm, "oa", false, "Permitir apenas golpes de braço")
+	flag.DurationVar(&o.Interval, "t", 1*time.Second, "Intervalo entre as séries")
+	flag.IntVar(&o.Level, "level", 0, "Nível de dificuldade: 0 libera todos os movimentos, N libera os N+1 primeiros do pack")
+	flag.IntVar(&rc.Rounds, "rounds", 1, "Número de rounds do treino")
+	flag.DurationVar(&rc.RoundDuration, "round-duration", 3*time.Minute, "Duração de cada round")
+	flag.DurationVar(&rc.RestDuration, "rest-duration", 1*time.Minute, "Duração do descanso entre rounds")
+	flag.DurationVar(&rc.WarmupDuration, "warmup", 0, "Duração do aquecimento antes do primeiro round")
+	flag.DurationVar(&rc.CooldownDuration, "cooldown", 0, "Duração da volta à calma após o último round")
+	packPath := flag.String("pack", "", "Caminho para um arquivo de movepack (JSON ou YAML). Se vazio, usa o pack embutido")
+	ruleSet := flag.String("ruleset", "", "Nome do rule set do movepack a aplicar além das regras automáticas de lado/perna")
+	soundPack := flag.String("sound-pack", "", "Nome do sound pack em ~/.config/manopla/sounds a usar. Se vazio, usa só o pack embutido")
+	listPacks := flag.Bool("list-packs", false, "Lista os sound packs disponíveis e sai")
+	useUI := flag.Bool("ui", false, "Abre uma interface de texto para acompanhar e controlar o treino")
+	seed := flag.Int64("seed", 0, "Semente do gerador aleatório. 0 (padrão) usa a hora atual")
+	logPath := flag.String("log", "", "Arquivo JSONL onde gravar as séries geradas nesta sessão")
+	replayPath := flag.String("replay", "", "Arquivo JSONL de uma sessão anterior a reproduzir, em vez de gerar séries novas")
+	useCombos := flag.Bool("combos", false, "Usa a biblioteca de combos canônicos do pack (1-2, 1-2-3, etc) em vez de séries aleatórias")
 	flag.Parse()
-	rand.Seed(2)
 
-	var possibleMoves = []move{
-		{name: "jab", front: true, back: false},
-		{name: "direto", front: false, back: true},
-		{name: "cruza", front: true, back: true, prob: 0.3},
-		{name: "chuta", front: true, back: true, leg: true},
-		{name: "tip", front: true, back: true, leg: true, prob: 0.5},
-		{name: "upper", front: true, back: true, prob: 0.3},
-		{name: "cotovelo", front: true, back: true, prob: 0.5},
-		{name: "joelho", front: true, back: true, leg: true, prob: 0.5},
+	if *listPacks {
+		packs, err := audio.ListPacks()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, p := range packs {
+			fmt.Println(p)
+		}
+		return
 	}
 
-	possibleMoves = applyGlobalOptions(possibleMoves, o)
-
-	sr := beep.SampleRate(48000)
-	err := speaker.Init(sr, sr.N(time.Second/10))
-	if err != nil {
-		panic(err)
+	if *seed != 0 {
+		rand.Seed(*seed)
+	} else {
+		rand.Seed(time.Now().UnixNano())
 	}
 
-	isFront := true
-
-	finish := make(chan struct{})
-	go func() {
-		_, err := os.Stdin.Read([]byte{0})
+	pack := movepack.Default()
+	if *packPath != "" {
+		p, err := movepack.Load(*packPath)
 		if err != nil {
-			log.Println(err)
-		}
-		close(finish)
-	}()
-
-	for {
-
-		moves := []move{}
-		s := ""
-		if isFront {
-			s += "F:"
-		} else {
-			s += "T:"
+			log.Fatal(err)
 		}
+		pack = p
+	}
 
-		for i := 0; i < o.seqSize; i++ {
-			move := nextMove(possibleMoves, rulesOptions{
-				previousMoves: moves,
-				seqSize:       o.seqSize,
-				isFront:       isFront,
-			})
-			moves = append(moves, move)
-			isFront = !isFront
-			s += " " + move.name
+	if *ruleSet != "" {
+		rules, err := pack.RuleSetRules(*ruleSet)
+		if err != nil {
+			log.Fatal(err)
 		}
+		o.ExtraRules = rules
+	}
 
-		fmt.Println(s)
+	possibleMoves := pack.ToMoves()
 
-		for _, m := range moves {
-			err := playAudio(m.name)
-			if err != nil {
-				panic(err)
-			}
-		}
+	sr := beep.SampleRate(48000)
+	err := speaker.Init(sr, sr.N(time.Second/10))
+	if err != nil {
+		panic(err)
+	}
 
-		select {
-		case <-finish:
-			return
-		case <-time.After(o.interval):
+	diskDir := ""
+	if *soundPack != "" {
+		dir, err := audio.PacksDir()
+		if err != nil {
+			log.Fatal(err)
 		}
+		diskDir = filepath.Join(dir, *soundPack)
 	}
-}
+	player := audio.NewSamplePlayer(fs, diskDir, pack.AudioFor)
 
-func applyGlobalOptions(moves []move, o globalOptions) []move {
-	result := []move{}
+	names := []string{"start", "rest", "10-seconds-left"}
+	for _, m := range possibleMoves {
+		names = append(names, m.Name)
+	}
+	if err := player.Preload(names); err != nil {
+		log.Fatal(err)
+	}
 
-	for _, m := range moves {
-		if o.onlyLeg && !m.leg {
-			continue
-		}
+	s := training.NewSession(possibleMoves, o, rc, player.Play)
+	s.PlaySequence = player.PlaySequence
 
-		if o.onlyArm && m.leg {
-			continue
+	if *logPath != "" {
+		rec, err := session.NewRecorder(*logPath)
+		if err != nil {
+			log.Fatal(err)
 		}
+		defer rec.Close()
 
-		if !o.onlyLeg && m.leg && (o.seqSize > 1 && o.seqSize < 4) {
-			continue
+		s.OnCombo = func(moves []training.Move) {
+			if err := rec.Record(moves); err != nil {
+				log.Println(err)
+			}
 		}
-
-		result = append(result, m)
 	}
 
-	if o.maxDistinct != 0 && len(result) > o.maxDistinct {
-		result = result[:o.maxDistinct]
+	if *replayPath != "" && *useCombos {
+		log.Fatal("-replay e -combos são modos de geração de série mutuamente exclusivos")
 	}
 
-	return result
-}
-
-func nextMove(possibleMoves []move, o rulesOptions) move {
-	rules := []rule{}
-	rules = append(rules, probabilityRule)
-
-	// somente movimentos permitidos para aquele lado, ex:
-	// jab só na frente, direto só com o braço de trás
-	if o.isFront {
-		rules = append(rules, frontRule)
-	} else {
-		rules = append(rules, backRule)
-	}
-
-	hasLeg := false
-	hasArm := false
-
-	for _, m := range possibleMoves {
-		if m.leg {
-			hasLeg = true
-		} else {
-			hasArm = true
+	if *replayPath != "" {
+		replay, err := session.NewReplayer(*replayPath)
+		if err != nil {
+			log.Fatal(err)
 		}
-	}
 
-	if hasLeg && hasArm {
-		// exigir perna somente para *ultimo* golpe quando forem 4 ou mais movimentos
-		// caso contrário, proibir perna caso não seja uma série de 1 só movimento
-		if o.seqSize >= 4 && len(o.previousMoves) == o.seqSize-1 {
-			rules = append(rules, mustBeLegRule)
-		} else if o.seqSize != 1 {
-			rules = append(rules, mustNotBeLegRule)
+		s.ComboSource = func() ([]training.Move, bool) {
+			return replay.Next(possibleMoves)
 		}
 	}
 
-	allowedMoves := applyRules(possibleMoves, rules)
-	i := rand.Intn(len(allowedMoves))
-	return allowedMoves[i]
-}
-
-func applyRules(possibleMoves []move, rules []rule) []move {
-	allowedMoves := []move{}
-
-	// filtra os movimentos de acordo com as regras
-	for _, m := range possibleMoves {
-		pass := true
+	if *useCombos {
+		s.ComboSource = pack.RandomCombo
+	}
 
-		for _, r := range rules {
-			if !r(m) {
-				pass = false
-				break
-			}
+	if *useUI {
+		u, err := ui.New(s)
+		if err != nil {
+			log.Fatal(err)
 		}
-
-		if pass {
-			allowedMoves = append(allowedMoves, m)
+		if err := u.Run(); err != nil {
+			log.Fatal(err)
 		}
+		return
 	}
 
-	return allowedMoves
-}
+	quit := make(chan struct{})
+	go func() {
+		_, err := os.Stdin.Read([]byte{0})
+		if err != nil {
+			log.Println(err)
+		}
+		close(quit)
+	}()
 
-func playAudio(name string) error {
-	f, err := fs.Open("sound/" + name + ".mp3")
-	if err != nil {
-		return err
+	s.Quit = quit
+	s.OnPhase = func(e training.PhaseEvent) {
+		fmt.Printf("[%s] round %d, faltam %s\n", e.Phase, e.Round, e.Remaining.Round(time.Second))
 	}
-	defer f.Close()
 
-	streamer, _, err := mp3.Decode(f)
-	if err != nil {
-		return err
+	if err := s.Run(); err != nil {
+		log.Fatal(err)
 	}
-	defer streamer.Close()
-
-	done := make(chan struct{})
-
-	speaker.Play(beep.Seq(streamer, beep.Callback(func() {
-		done <- struct{}{}
-	})))
-
-	<-done
-
-	return nil
-}
-
-// o `level` define quais movimentos irão aparecer durante o treino.
-// `level` = 0 (padrão) significa que todos os movimentos poderão parecer.
-// `level` = 1 significa que somente os dois primeiros movimentos irão aparecer (jab e direto).
-// `level` = 2 significa que os 3 primeiros movimentos irão aparecer.
-// etc
-
-func probabilityRule(m move) bool {
-	p := rand.Float64()
-	return m.prob <= p
-}
-
-func frontRule(m move) bool {
-	return m.front
-}
-
-func backRule(m move) bool {
-	return m.back
-}
-
-func mustBeLegRule(m move) bool {
-	return m.leg
-}
-
-func mustNotBeLegRule(m move) bool {
-	return !m.leg
 }
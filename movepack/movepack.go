@@ -0,0 +1,265 @@
+// Package movepack carrega a lista de movimentos e os conjuntos de
+// regras de um arquivo de configuração (JSON ou YAML), para que um
+// "pacote" de golpes (boxe, MMA, kickboxing, etc) possa ser
+// distribuído e trocado sem recompilar o manopla.
+package movepack
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/igoracmelo/manopla-go/training"
+	"gopkg.in/yaml.v3"
+)
+
+// MoveDef é a representação em arquivo de um training.Move, incluindo
+// o caminho do áudio associado a ele.
+type MoveDef struct {
+	Name  string  `json:"name" yaml:"name"`
+	Front bool    `json:"front" yaml:"front"`
+	Back  bool    `json:"back" yaml:"back"`
+	Leg   bool    `json:"leg" yaml:"leg"`
+	Prob  float64 `json:"prob" yaml:"prob"`
+	Audio string  `json:"audio" yaml:"audio"`
+}
+
+// RuleSet nomeia regras adicionais de perna a exigir por cima das
+// regras automáticas de lado (front/back, já aplicadas por NextMove
+// conforme IsFront), ex: "pernas" só libera golpes de perna, enquanto
+// "sem-pernas" os proíbe por completo — não inclua "front"/"back" aqui,
+// eles já são aplicados automaticamente e, somados de novo, bloqueiam
+// golpes legítimos (ex: jab, que é front-only).
+type RuleSet struct {
+	Name  string   `json:"name" yaml:"name"`
+	Rules []string `json:"rules" yaml:"rules"`
+}
+
+// ComboDef é uma combinação canônica nomeada (ex: "1-2", "jab-direto-
+// upper-chuta"), usada pelo modo `-combos` no lugar da geração
+// aleatória de séries.
+type ComboDef struct {
+	Name   string   `json:"name" yaml:"name"`
+	Moves  []string `json:"moves" yaml:"moves"`
+	Weight float64  `json:"weight" yaml:"weight"`
+}
+
+// Pack é um pacote de movimentos completo: a lista de golpes
+// disponíveis, os conjuntos de regras nomeados que podem ser
+// aplicados a eles, e a biblioteca de combos canônicos.
+type Pack struct {
+	Moves    []MoveDef  `json:"moves" yaml:"moves"`
+	RuleSets []RuleSet  `json:"ruleSets" yaml:"ruleSets"`
+	Combos   []ComboDef `json:"combos" yaml:"combos"`
+}
+
+// Load lê um Pack de um arquivo JSON ou YAML, decidindo o formato pela
+// extensão (.json, .yaml ou .yml).
+func Load(path string) (*Pack, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Pack{}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, p)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, p)
+	default:
+		return nil, fmt.Errorf("movepack: extensão de arquivo não suportada: %q", path)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("movepack: erro ao ler %q: %w", path, err)
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("movepack: %q inválido: %w", path, err)
+	}
+
+	return p, nil
+}
+
+// Validate garante que o pack carregado é utilizável: nomes de
+// movimento não vazios e únicos, probabilidades dentro do intervalo
+// válido, e rule sets referenciando apenas regras conhecidas.
+func (p *Pack) Validate() error {
+	if len(p.Moves) == 0 {
+		return fmt.Errorf("pack não possui nenhum movimento")
+	}
+
+	seen := map[string]bool{}
+	for _, m := range p.Moves {
+		if m.Name == "" {
+			return fmt.Errorf("movimento com nome vazio")
+		}
+		if seen[m.Name] {
+			return fmt.Errorf("movimento duplicado: %q", m.Name)
+		}
+		seen[m.Name] = true
+
+		if m.Prob < 0 || m.Prob > 1 {
+			return fmt.Errorf("movimento %q: prob deve estar entre 0 e 1, recebeu %v", m.Name, m.Prob)
+		}
+	}
+
+	for _, rs := range p.RuleSets {
+		for _, name := range rs.Rules {
+			if _, ok := knownRules[name]; !ok {
+				return fmt.Errorf("rule set %q: regra desconhecida %q", rs.Name, name)
+			}
+		}
+	}
+
+	for _, c := range p.Combos {
+		if len(c.Moves) == 0 {
+			return fmt.Errorf("combo %q: não possui nenhum movimento", c.Name)
+		}
+		for _, name := range c.Moves {
+			if !seen[name] {
+				return fmt.Errorf("combo %q: movimento desconhecido %q", c.Name, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// knownRules mapeia os nomes de regra aceitos em `RuleSet.Rules` para
+// as training.Rule que eles de fato aplicam. "front"/"back" ficam de
+// fora de propósito: NextMove já as aplica automaticamente conforme o
+// lado da vez, e somá-las de novo aqui exigiria front && back ao mesmo
+// tempo, excluindo golpes legítimos.
+var knownRules = map[string]training.Rule{
+	"mustBeLeg":    training.MustBeLegRule,
+	"mustNotBeLeg": training.MustNotBeLegRule,
+}
+
+// RuleSetRules resolve um RuleSet nomeado do pack para as
+// training.Rule correspondentes, prontas para serem usadas como
+// training.GlobalOptions.ExtraRules.
+func (p *Pack) RuleSetRules(name string) ([]training.Rule, error) {
+	for _, rs := range p.RuleSets {
+		if rs.Name != name {
+			continue
+		}
+
+		rules := make([]training.Rule, len(rs.Rules))
+		for i, rn := range rs.Rules {
+			rules[i] = knownRules[rn]
+		}
+		return rules, nil
+	}
+
+	return nil, fmt.Errorf("movepack: rule set desconhecido: %q", name)
+}
+
+// ToMoves converte o pack para os training.Move que o motor de geração
+// de combos entende.
+func (p *Pack) ToMoves() []training.Move {
+	moves := make([]training.Move, len(p.Moves))
+	for i, m := range p.Moves {
+		moves[i] = training.Move{
+			Name:  m.Name,
+			Front: m.Front,
+			Back:  m.Back,
+			Leg:   m.Leg,
+			Prob:  m.Prob,
+		}
+	}
+	return moves
+}
+
+// RandomCombo sorteia um combo da biblioteca de combos do pack,
+// ponderado por Weight (pesos <= 0 valem 1), e o resolve para os
+// training.Move correspondentes. ok é false se o pack não tiver
+// nenhum combo cadastrado.
+func (p *Pack) RandomCombo() ([]training.Move, bool) {
+	if len(p.Combos) == 0 {
+		return nil, false
+	}
+
+	total := 0.0
+	for _, c := range p.Combos {
+		total += comboWeight(c)
+	}
+
+	r := rand.Float64() * total
+	for _, c := range p.Combos {
+		w := comboWeight(c)
+		if r < w {
+			return p.resolveCombo(c), true
+		}
+		r -= w
+	}
+
+	return p.resolveCombo(p.Combos[len(p.Combos)-1]), true
+}
+
+func comboWeight(c ComboDef) float64 {
+	if c.Weight <= 0 {
+		return 1
+	}
+	return c.Weight
+}
+
+func (p *Pack) resolveCombo(c ComboDef) []training.Move {
+	moves := make([]training.Move, len(c.Moves))
+	for i, name := range c.Moves {
+		moves[i] = p.findMove(name)
+	}
+	return moves
+}
+
+func (p *Pack) findMove(name string) training.Move {
+	for _, m := range p.Moves {
+		if m.Name == name {
+			return training.Move{Name: m.Name, Front: m.Front, Back: m.Back, Leg: m.Leg, Prob: m.Prob}
+		}
+	}
+	return training.Move{Name: name}
+}
+
+// AudioFor retorna o caminho de áudio configurado para um movimento,
+// ou o próprio nome caso nenhum tenha sido especificado (mantendo a
+// convenção `sound/<nome>.mp3` do pack embutido).
+func (p *Pack) AudioFor(moveName string) string {
+	for _, m := range p.Moves {
+		if m.Name == moveName && m.Audio != "" {
+			return m.Audio
+		}
+	}
+	return moveName
+}
+
+// Default é o pack embutido, equivalente ao `possibleMoves` hardcoded
+// original, usado quando nenhum `-pack` é informado.
+func Default() *Pack {
+	return &Pack{
+		Moves: []MoveDef{
+			{Name: "jab", Front: true, Back: false},
+			{Name: "direto", Front: false, Back: true},
+			{Name: "cruza", Front: true, Back: true, Prob: 0.3},
+			{Name: "chuta", Front: true, Back: true, Leg: true},
+			{Name: "tip", Front: true, Back: true, Leg: true, Prob: 0.5},
+			{Name: "upper", Front: true, Back: true, Prob: 0.3},
+			{Name: "cotovelo", Front: true, Back: true, Prob: 0.5},
+			{Name: "joelho", Front: true, Back: true, Leg: true, Prob: 0.5},
+		},
+		RuleSets: []RuleSet{
+			{Name: "sem-pernas", Rules: []string{"mustNotBeLeg"}},
+		},
+		Combos: []ComboDef{
+			{Name: "1-2", Moves: []string{"jab", "direto"}, Weight: 1},
+			{Name: "1-2-3", Moves: []string{"jab", "direto", "cruza"}, Weight: 1},
+			{Name: "1-2-3-2", Moves: []string{"jab", "direto", "cruza", "direto"}, Weight: 1},
+			{Name: "jab-direto-upper-chuta", Moves: []string{"jab", "direto", "upper", "chuta"}, Weight: 0.5},
+		},
+	}
+}
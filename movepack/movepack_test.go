@@ -0,0 +1,95 @@
+package movepack
+
+import "testing"
+
+func validPack() *Pack {
+	return &Pack{
+		Moves: []MoveDef{
+			{Name: "jab", Front: true, Prob: 0.3},
+			{Name: "direto", Back: true},
+		},
+		RuleSets: []RuleSet{
+			{Name: "sem-pernas", Rules: []string{"mustNotBeLeg"}},
+		},
+		Combos: []ComboDef{
+			{Name: "1-2", Moves: []string{"jab", "direto"}, Weight: 1},
+		},
+	}
+}
+
+func TestValidateAcceptsWellFormedPack(t *testing.T) {
+	if err := validPack().Validate(); err != nil {
+		t.Errorf("pack válido rejeitado: %v", err)
+	}
+}
+
+func TestValidateRejectsDuplicateMoveName(t *testing.T) {
+	p := validPack()
+	p.Moves = append(p.Moves, MoveDef{Name: "jab"})
+	if err := p.Validate(); err == nil {
+		t.Error("esperava erro para movimento duplicado, veio nil")
+	}
+}
+
+func TestValidateRejectsProbOutOfRange(t *testing.T) {
+	p := validPack()
+	p.Moves[0].Prob = 1.5
+	if err := p.Validate(); err == nil {
+		t.Error("esperava erro para prob fora do intervalo, veio nil")
+	}
+}
+
+func TestValidateRejectsUnknownRuleSetRule(t *testing.T) {
+	p := validPack()
+	p.RuleSets[0].Rules = []string{"voa"}
+	if err := p.Validate(); err == nil {
+		t.Error("esperava erro para regra desconhecida, veio nil")
+	}
+}
+
+func TestValidateRejectsComboWithUnknownMove(t *testing.T) {
+	p := validPack()
+	p.Combos[0].Moves = []string{"chuta"}
+	if err := p.Validate(); err == nil {
+		t.Error("esperava erro para combo com movimento desconhecido, veio nil")
+	}
+}
+
+func TestRuleSetRulesUnknownName(t *testing.T) {
+	p := validPack()
+	if _, err := p.RuleSetRules("mma"); err == nil {
+		t.Error("esperava erro para rule set desconhecido, veio nil")
+	}
+}
+
+func TestRandomComboSingleEntryAlwaysWins(t *testing.T) {
+	p := validPack()
+
+	moves, ok := p.RandomCombo()
+	if !ok {
+		t.Fatal("esperava ok=true com um combo cadastrado")
+	}
+	if len(moves) != 2 || moves[0].Name != "jab" || moves[1].Name != "direto" {
+		t.Errorf("esperava [jab direto] resolvidos, veio %v", moves)
+	}
+	if !moves[0].Front {
+		t.Errorf("esperava jab resolvido com Front=true, veio %+v", moves[0])
+	}
+}
+
+func TestRandomComboNoneConfigured(t *testing.T) {
+	p := validPack()
+	p.Combos = nil
+
+	if _, ok := p.RandomCombo(); ok {
+		t.Error("esperava ok=false sem combos cadastrados")
+	}
+}
+
+func TestFindMoveFallsBackToNameOnly(t *testing.T) {
+	p := validPack()
+	m := p.findMove("chuta")
+	if m.Name != "chuta" || m.Front || m.Back || m.Leg {
+		t.Errorf("esperava Move só com o nome para movimento desconhecido, veio %+v", m)
+	}
+}
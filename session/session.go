@@ -0,0 +1,120 @@
+// Package session grava e reproduz os combos gerados em um treino, em
+// um arquivo JSONL, permitindo repetir um treino específico (um
+// "treino do dia" compartilhável) ou conferir depois o que foi
+// sorteado.
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/igoracmelo/manopla-go/training"
+)
+
+// Entry é uma linha do arquivo de log: uma série de movimentos e
+// quando ela foi gerada.
+type Entry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Moves     []string  `json:"moves"`
+}
+
+// Recorder acrescenta uma Entry por série gerada a um arquivo JSONL.
+type Recorder struct {
+	f   *os.File
+	enc *json.Encoder
+	now func() time.Time
+}
+
+// NewRecorder abre (ou cria) `path` para acrescentar entradas.
+func NewRecorder(path string) (*Recorder, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Recorder{f: f, enc: json.NewEncoder(f), now: time.Now}, nil
+}
+
+// Record grava a série `moves` como uma nova Entry.
+func (r *Recorder) Record(moves []training.Move) error {
+	names := make([]string, len(moves))
+	for i, m := range moves {
+		names[i] = m.Name
+	}
+
+	return r.enc.Encode(Entry{Timestamp: r.now(), Moves: names})
+}
+
+// Close fecha o arquivo de log.
+func (r *Recorder) Close() error {
+	return r.f.Close()
+}
+
+// Replayer lê de volta as Entry de um arquivo JSONL gravado por um
+// Recorder, devolvendo-as uma a uma na ordem em que foram gravadas.
+type Replayer struct {
+	entries []Entry
+	i       int
+}
+
+// NewReplayer carrega todas as entradas de `path` na memória.
+func NewReplayer(path string) (*Replayer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := &Replayer{}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var e Entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			return nil, fmt.Errorf("session: linha inválida em %q: %w", path, err)
+		}
+		r.entries = append(r.entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Next devolve a próxima série gravada, resolvendo cada nome de
+// movimento contra `known` para recuperar front/back/leg/prob. Um
+// nome sem correspondência em `known` vira um Move só com o nome.
+// ok é false quando todas as entradas já foram consumidas.
+func (r *Replayer) Next(known []training.Move) (moves []training.Move, ok bool) {
+	if r.i >= len(r.entries) {
+		return nil, false
+	}
+
+	entry := r.entries[r.i]
+	r.i++
+
+	moves = make([]training.Move, len(entry.Moves))
+	for i, name := range entry.Moves {
+		moves[i] = lookup(known, name)
+	}
+
+	return moves, true
+}
+
+func lookup(known []training.Move, name string) training.Move {
+	for _, m := range known {
+		if m.Name == name {
+			return m
+		}
+	}
+	return training.Move{Name: name}
+}
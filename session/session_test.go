@@ -0,0 +1,133 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/igoracmelo/manopla-go/training"
+)
+
+func TestRecorderReplayerRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "workout.jsonl")
+
+	rec, err := NewRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	combos := [][]training.Move{
+		{{Name: "jab", Front: true}, {Name: "direto", Back: true}},
+		{{Name: "chuta", Leg: true}},
+	}
+	for _, combo := range combos {
+		if err := rec.Record(combo); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	known := []training.Move{
+		{Name: "jab", Front: true},
+		{Name: "direto", Back: true},
+		{Name: "chuta", Leg: true, Prob: 0.5},
+	}
+
+	replay, err := NewReplayer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range combos {
+		got, ok := replay.Next(known)
+		if !ok {
+			t.Fatalf("combo %d: esperava ok=true, veio false", i)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("combo %d: esperava %d movimentos, veio %d", i, len(want), len(got))
+		}
+		for j, m := range got {
+			if m.Name != want[j].Name {
+				t.Errorf("combo %d, movimento %d: esperava nome %q, veio %q", i, j, want[j].Name, m.Name)
+			}
+		}
+	}
+
+	// o movimento "chuta" veio de `known`, então deve ter Leg/Prob
+	// resolvidos, não só o nome.
+	last, _ := NewReplayer(path)
+	last.Next(known)
+	chuta, _ := last.Next(known)
+	if !chuta[0].Leg || chuta[0].Prob != 0.5 {
+		t.Errorf("esperava chuta resolvido contra known (Leg=true, Prob=0.5), veio %+v", chuta[0])
+	}
+
+	if _, ok := replay.Next(known); ok {
+		t.Error("esperava ok=false depois de consumir todas as entradas")
+	}
+}
+
+func TestReplayerUnknownMoveFallsBackToName(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "workout.jsonl")
+
+	rec, err := NewRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Record([]training.Move{{Name: "golpe-desconhecido"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	replay, err := NewReplayer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok := replay.Next(nil)
+	if !ok || len(got) != 1 {
+		t.Fatalf("esperava uma série com 1 movimento, veio %+v (ok=%v)", got, ok)
+	}
+	if got[0] != (training.Move{Name: "golpe-desconhecido"}) {
+		t.Errorf("esperava Move só com o nome, veio %+v", got[0])
+	}
+}
+
+func TestNewReplayerSkipsEmptyLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "workout.jsonl")
+
+	rec, err := NewRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Record([]training.Move{{Name: "jab"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("\n\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	replay, err := NewReplayer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(replay.entries) != 1 {
+		t.Fatalf("esperava 1 entrada ignorando linhas vazias, veio %d", len(replay.entries))
+	}
+}
@@ -0,0 +1,582 @@
+// Package training implementa o motor de geração de combos e o
+// agendador de rounds (aquecimento, round, descanso, volta à calma)
+// usado pelo manopla para tocar os treinos.
+package training
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+type Move struct {
+	Name  string
+	Front bool
+	Back  bool
+	Leg   bool
+
+	// a probabilidade é um valor percentual inverso, para evitar
+	// de precisar preenchê-la para cada `Move`.
+	// 0.0 significa que o movimento pode sempre aparecer, enquanto 1.0
+	// significa que o movimento nunca poderá aparecer
+	Prob float64
+}
+
+type Rule func(Move) bool
+
+type GlobalOptions struct {
+	MaxDistinct int
+	SeqSize     int
+	OnlyArm     bool
+	OnlyLeg     bool
+	Interval    time.Duration
+
+	// Level restringe quais movimentos podem aparecer, pela ordem em
+	// que aparecem no pack: 0 (padrão) libera todos, N libera os N+1
+	// primeiros. Ver ApplyLevel.
+	Level int
+
+	// ExtraRules são regras adicionais a exigir de todo movimento
+	// sorteado, tipicamente vindas de um RuleSet nomeado do movepack
+	// selecionado (ver movepack.Pack.RuleSetRules). Pode ser nil.
+	ExtraRules []Rule
+}
+
+// ApplyLevel restringe `moves` aos N+1 primeiros, na ordem em que
+// aparecem no pack, onde N é `level`: level 0 libera todos os
+// movimentos, level 1 libera só os 2 primeiros (jab e direto no pack
+// padrão), level 2 libera os 3 primeiros, e assim por diante.
+func ApplyLevel(moves []Move, level int) []Move {
+	if level <= 0 {
+		return moves
+	}
+
+	count := level + 1
+	if count > len(moves) {
+		count = len(moves)
+	}
+
+	return moves[:count]
+}
+
+type RulesOptions struct {
+	IsFront       bool
+	PreviousMoves []Move
+	SeqSize       int
+
+	// ExtraRules são regras adicionais aplicadas por cima das regras
+	// automáticas de lado/perna, tipicamente vindas de um RuleSet
+	// nomeado de um movepack (ver movepack.Pack.RuleSetRules).
+	ExtraRules []Rule
+}
+
+// Phase identifica em qual etapa do round o Session está.
+type Phase string
+
+const (
+	PhaseWarmup   Phase = "warmup"
+	PhaseRound    Phase = "round"
+	PhaseRest     Phase = "rest"
+	PhaseCooldown Phase = "cooldown"
+)
+
+// PhaseEvent é emitido toda vez que o Session muda de fase ou avança o
+// relógio dentro de uma fase, para que UIs (o log no stdout de `main`
+// ou o TUI do pacote ui) possam reagir.
+type PhaseEvent struct {
+	Phase     Phase
+	Round     int
+	Elapsed   time.Duration
+	Remaining time.Duration
+}
+
+// RoundConfig descreve a estrutura de um treino em rounds, no estilo
+// boxe/muay-thai: aquecimento, N rounds com descanso entre eles, e
+// volta à calma.
+type RoundConfig struct {
+	Rounds           int
+	RoundDuration    time.Duration
+	RestDuration     time.Duration
+	WarmupDuration   time.Duration
+	CooldownDuration time.Duration
+}
+
+// Control agrupa os canais pelos quais uma UI comanda um Session já em
+// execução. Todos os canais têm buffer 1: uma UI envia um sinal e
+// segue em frente sem esperar o loop do Session atendê-lo.
+type Control struct {
+	TogglePause   chan struct{}
+	Skip          chan struct{}
+	SetInterval   chan time.Duration
+	ToggleOnlyArm chan struct{}
+	ToggleOnlyLeg chan struct{}
+}
+
+// NewControl cria um Control pronto para ser ligado a um Session.
+func NewControl() *Control {
+	return &Control{
+		TogglePause:   make(chan struct{}, 1),
+		Skip:          make(chan struct{}, 1),
+		SetInterval:   make(chan time.Duration, 1),
+		ToggleOnlyArm: make(chan struct{}, 1),
+		ToggleOnlyLeg: make(chan struct{}, 1),
+	}
+}
+
+// Session é o runner de um treino completo. Ele é o dono do loop que
+// antes vivia dentro de `main`: a cada round ele gera séries de
+// movimentos respeitando `Global` e as regras de NextMove, tocando os áudios
+// correspondentes, até o tempo do round acabar.
+type Session struct {
+	Moves  []Move
+	Global GlobalOptions
+	Rounds RoundConfig
+
+	// PlayAudio toca o áudio de um movimento ou de um aviso de fase
+	// (ex: "start", "rest", "10-seconds-left"). É injetado de fora para
+	// que o Session não dependa de como o áudio é de fato reproduzido.
+	PlayAudio func(name string) error
+
+	// PlaySequence, se não for nil, toca uma série inteira de uma vez,
+	// retornando quanto tempo ela ocupa, em vez de tocar cada movimento
+	// com PlayAudio um de cada vez — isso é o que permite encadear os
+	// golpes de uma série sem o atraso serial de "toca, espera acabar,
+	// toca o próximo". Se nil, runRound cai para PlayAudio por movimento.
+	PlaySequence func(names []string) (time.Duration, error)
+
+	// OnPhase é chamado a cada mudança/avanço de fase, servindo de
+	// ponto de extensão para UIs (o TUI do pacote ui, web, etc). Pode ser nil.
+	OnPhase func(PhaseEvent)
+
+	// OnCombo é chamado toda vez que uma nova série de movimentos é
+	// gerada, antes de ser tocada, para que uma UI possa mostrar a
+	// próxima série e manter um histórico das anteriores. Pode ser nil.
+	OnCombo func([]Move)
+
+	// ComboSource, se não for nil, substitui a geração aleatória de
+	// séries por séries vindas de outra fonte (ex: séries gravadas no
+	// modo replay ou combos da biblioteca do movepack). Retorna
+	// ok=false para indicar que não há mais séries, encerrando o round.
+	ComboSource func() (moves []Move, ok bool)
+
+	// Quit, se não for nil, encerra o Session assim que for fechado.
+	Quit <-chan struct{}
+
+	// Control, se não for nil, permite pausar/retomar, pular o restante
+	// da espera até a próxima série, trocar o intervalo e alternar
+	// onlyArm/onlyLeg em tempo de execução, tipicamente ligado a uma UI.
+	// Os comandos só são atendidos durante essa espera: uma série já em
+	// reprodução é tocada até o fim.
+	Control *Control
+
+	// now existe só para permitir testes determinísticos do agendamento.
+	now func() time.Time
+}
+
+// NewSession monta um Session pronto para rodar com o relógio real.
+func NewSession(moves []Move, global GlobalOptions, rounds RoundConfig, playAudio func(string) error) *Session {
+	return &Session{
+		Moves:     moves,
+		Global:    global,
+		Rounds:    rounds,
+		PlayAudio: playAudio,
+		now:       time.Now,
+	}
+}
+
+// Run conduz o treino do aquecimento até a volta à calma, retornando
+// quando todos os rounds acabarem ou quando Quit for fechado.
+func (s *Session) Run() error {
+	if s.Rounds.WarmupDuration > 0 {
+		if err := s.runTimedPhase(PhaseWarmup, 0, s.Rounds.WarmupDuration); err != nil {
+			return err
+		}
+		if s.quit() {
+			return nil
+		}
+	}
+
+	for round := 1; round <= s.Rounds.Rounds; round++ {
+		if err := s.announce("start"); err != nil {
+			return err
+		}
+
+		if err := s.runRound(round); err != nil {
+			return err
+		}
+
+		if s.quit() {
+			return nil
+		}
+
+		if round < s.Rounds.Rounds && s.Rounds.RestDuration > 0 {
+			if err := s.announce("rest"); err != nil {
+				return err
+			}
+			if err := s.runTimedPhase(PhaseRest, round, s.Rounds.RestDuration); err != nil {
+				return err
+			}
+			if s.quit() {
+				return nil
+			}
+		}
+	}
+
+	if s.Rounds.CooldownDuration > 0 {
+		if err := s.runTimedPhase(PhaseCooldown, s.Rounds.Rounds, s.Rounds.CooldownDuration); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runRound gera e toca séries de movimentos até RoundDuration se
+// esgotar, aplicando a rampa de dificuldade de applyDifficultyRamp:
+// quanto mais avançado o round, menor o Prob dos movimentos. O tamanho
+// da série e o intervalo entre séries não mudam de um round para outro.
+func (s *Session) runRound(round int) error {
+	deadline := s.now().Add(s.Rounds.RoundDuration)
+	warned10s := false
+	isFront := true
+
+	for s.now().Before(deadline) {
+		remaining := deadline.Sub(s.now())
+		if !warned10s && s.Rounds.RoundDuration > 10*time.Second && remaining <= 10*time.Second {
+			warned10s = true
+			if err := s.announce("10-seconds-left"); err != nil {
+				return err
+			}
+		}
+
+		s.emit(PhaseRound, round, s.Rounds.RoundDuration-remaining, remaining)
+
+		var seq []Move
+		if s.ComboSource != nil {
+			var ok bool
+			seq, ok = s.ComboSource()
+			if !ok {
+				return nil
+			}
+		} else {
+			// recalcula a cada série: onlyArm/onlyLeg podem ter sido
+			// alternados pela UI desde a série anterior.
+			moves := applyDifficultyRamp(ApplyGlobalOptions(s.Moves, s.Global), s.Global, round)
+
+			seq = []Move{}
+			for i := 0; i < s.Global.SeqSize; i++ {
+				m, err := NextMove(moves, RulesOptions{
+					PreviousMoves: seq,
+					SeqSize:       s.Global.SeqSize,
+					IsFront:       isFront,
+					ExtraRules:    s.Global.ExtraRules,
+				})
+				if err != nil {
+					return err
+				}
+				seq = append(seq, m)
+				isFront = !isFront
+			}
+		}
+
+		if s.OnCombo != nil {
+			s.OnCombo(seq)
+		}
+
+		if s.PlaySequence != nil {
+			names := make([]string, len(seq))
+			for i, m := range seq {
+				names[i] = m.Name
+			}
+
+			dur, err := s.PlaySequence(names)
+			if err != nil {
+				return err
+			}
+
+			if s.sleepOrQuit(dur) {
+				return nil
+			}
+		} else {
+			for _, m := range seq {
+				if err := s.PlayAudio(m.Name); err != nil {
+					return err
+				}
+			}
+		}
+
+		if s.quit() {
+			return nil
+		}
+
+		if s.wait() {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// wait aguarda o intervalo entre séries, atendendo comandos de Control
+// (pausa, pular, trocar intervalo, alternar onlyArm/onlyLeg) enquanto
+// espera. Retorna true se o Session deve encerrar.
+func (s *Session) wait() bool {
+	if s.Control == nil {
+		select {
+		case <-s.Quit:
+			return true
+		case <-time.After(s.Global.Interval):
+			return false
+		}
+	}
+
+	timer := time.NewTimer(s.Global.Interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-s.Quit:
+			return true
+		case <-timer.C:
+			return false
+		case <-s.Control.Skip:
+			return false
+		case iv := <-s.Control.SetInterval:
+			s.Global.Interval = iv
+		case <-s.Control.ToggleOnlyArm:
+			s.Global.OnlyArm = !s.Global.OnlyArm
+			if s.Global.OnlyArm {
+				s.Global.OnlyLeg = false
+			}
+		case <-s.Control.ToggleOnlyLeg:
+			s.Global.OnlyLeg = !s.Global.OnlyLeg
+			if s.Global.OnlyLeg {
+				s.Global.OnlyArm = false
+			}
+		case <-s.Control.TogglePause:
+			select {
+			case <-s.Quit:
+				return true
+			case <-s.Control.TogglePause:
+			}
+		}
+	}
+}
+
+// runTimedPhase apenas marca o tempo de uma fase sem movimentos
+// (aquecimento, descanso, volta à calma), emitindo PhaseEvent para que
+// uma UI possa desenhar uma contagem regressiva.
+func (s *Session) runTimedPhase(phase Phase, round int, d time.Duration) error {
+	deadline := s.now().Add(d)
+
+	for s.now().Before(deadline) {
+		remaining := deadline.Sub(s.now())
+		s.emit(phase, round, d-remaining, remaining)
+
+		if s.quit() {
+			return nil
+		}
+
+		select {
+		case <-s.Quit:
+			return nil
+		case <-time.After(minDuration(remaining, time.Second)):
+		}
+	}
+
+	return nil
+}
+
+func (s *Session) announce(cue string) error {
+	if s.PlayAudio == nil {
+		return nil
+	}
+	return s.PlayAudio(cue)
+}
+
+func (s *Session) emit(phase Phase, round int, elapsed, remaining time.Duration) {
+	if s.OnPhase == nil {
+		return
+	}
+	s.OnPhase(PhaseEvent{Phase: phase, Round: round, Elapsed: elapsed, Remaining: remaining})
+}
+
+// sleepOrQuit espera `d`, atendendo Quit enquanto isso, usado para
+// bloquear pela duração de uma série tocada inteira por PlaySequence.
+// Retorna true se o Session deve encerrar.
+func (s *Session) sleepOrQuit(d time.Duration) bool {
+	if s.Quit == nil {
+		time.Sleep(d)
+		return false
+	}
+
+	select {
+	case <-s.Quit:
+		return true
+	case <-time.After(d):
+		return false
+	}
+}
+
+func (s *Session) quit() bool {
+	if s.Quit == nil {
+		return false
+	}
+	select {
+	case <-s.Quit:
+		return true
+	default:
+		return false
+	}
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// applyDifficultyRamp deixa os rounds finais mais puxados, aumentando
+// levemente a chance de golpes com prob mais alta conforme o round
+// avança. Não mexe em `possibleMoves` global: opera sobre a cópia já
+// filtrada por ApplyGlobalOptions.
+func applyDifficultyRamp(moves []Move, o GlobalOptions, round int) []Move {
+	if round <= 1 {
+		return moves
+	}
+
+	ramped := make([]Move, len(moves))
+	for i, m := range moves {
+		m.Prob = m.Prob * (1 - float64(round-1)*0.1)
+		if m.Prob < 0 {
+			m.Prob = 0
+		}
+		ramped[i] = m
+	}
+
+	return ramped
+}
+
+func ApplyGlobalOptions(moves []Move, o GlobalOptions) []Move {
+	moves = ApplyLevel(moves, o.Level)
+
+	result := []Move{}
+
+	for _, m := range moves {
+		if o.OnlyLeg && !m.Leg {
+			continue
+		}
+
+		if o.OnlyArm && m.Leg {
+			continue
+		}
+
+		if !o.OnlyLeg && m.Leg && (o.SeqSize > 1 && o.SeqSize < 4) {
+			continue
+		}
+
+		result = append(result, m)
+	}
+
+	if o.MaxDistinct != 0 && len(result) > o.MaxDistinct {
+		result = result[:o.MaxDistinct]
+	}
+
+	return result
+}
+
+// NextMove sorteia o próximo movimento dentre `possibleMoves` que
+// passa nas regras aplicáveis. Retorna erro se nenhum movimento restar
+// depois de filtrado — o que pode acontecer com combinações válidas de
+// opções (ex: `-level 1 -ol` restringe o pack padrão a jab/direto, que
+// são golpes de braço, e depois os exclui todos por serem de braço).
+func NextMove(possibleMoves []Move, o RulesOptions) (Move, error) {
+	rules := []Rule{}
+	rules = append(rules, probabilityRule)
+
+	// somente movimentos permitidos para aquele lado, ex:
+	// jab só na frente, direto só com o braço de trás
+	if o.IsFront {
+		rules = append(rules, FrontRule)
+	} else {
+		rules = append(rules, BackRule)
+	}
+
+	hasLeg := false
+	hasArm := false
+
+	for _, m := range possibleMoves {
+		if m.Leg {
+			hasLeg = true
+		} else {
+			hasArm = true
+		}
+	}
+
+	if hasLeg && hasArm {
+		// exigir perna somente para *ultimo* golpe quando forem 4 ou mais movimentos
+		// caso contrário, proibir perna caso não seja uma série de 1 só movimento
+		if o.SeqSize >= 4 && len(o.PreviousMoves) == o.SeqSize-1 {
+			rules = append(rules, MustBeLegRule)
+		} else if o.SeqSize != 1 {
+			rules = append(rules, MustNotBeLegRule)
+		}
+	}
+
+	rules = append(rules, o.ExtraRules...)
+
+	allowedMoves := ApplyRules(possibleMoves, rules)
+	if len(allowedMoves) == 0 {
+		return Move{}, fmt.Errorf("training: nenhum movimento disponível para as opções atuais (confira -level, -ol, -oa e o movepack)")
+	}
+
+	i := rand.Intn(len(allowedMoves))
+	return allowedMoves[i], nil
+}
+
+func ApplyRules(possibleMoves []Move, rules []Rule) []Move {
+	allowedMoves := []Move{}
+
+	// filtra os movimentos de acordo com as regras
+	for _, m := range possibleMoves {
+		pass := true
+
+		for _, r := range rules {
+			if !r(m) {
+				pass = false
+				break
+			}
+		}
+
+		if pass {
+			allowedMoves = append(allowedMoves, m)
+		}
+	}
+
+	return allowedMoves
+}
+
+func probabilityRule(m Move) bool {
+	p := rand.Float64()
+	return m.Prob <= p
+}
+
+// FrontRule, BackRule, MustBeLegRule e MustNotBeLegRule são exportadas
+// para que outros pacotes (ex: movepack, ao resolver um RuleSet
+// nomeado) possam referenciá-las pelo nome.
+func FrontRule(m Move) bool {
+	return m.Front
+}
+
+func BackRule(m Move) bool {
+	return m.Back
+}
+
+func MustBeLegRule(m Move) bool {
+	return m.Leg
+}
+
+func MustNotBeLegRule(m Move) bool {
+	return !m.Leg
+}
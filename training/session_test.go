@@ -0,0 +1,64 @@
+package training
+
+import "testing"
+
+func TestApplyLevel(t *testing.T) {
+	moves := []Move{{Name: "jab"}, {Name: "direto"}, {Name: "cruza"}, {Name: "chuta"}}
+
+	if got := ApplyLevel(moves, 0); len(got) != len(moves) {
+		t.Errorf("level 0: esperava todos os %d movimentos, veio %d", len(moves), len(got))
+	}
+
+	if got := ApplyLevel(moves, 1); len(got) != 2 || got[0].Name != "jab" || got[1].Name != "direto" {
+		t.Errorf("level 1: esperava [jab direto], veio %v", got)
+	}
+
+	if got := ApplyLevel(moves, 100); len(got) != len(moves) {
+		t.Errorf("level maior que o pack: esperava saturar em %d, veio %d", len(moves), len(got))
+	}
+}
+
+func TestApplyDifficultyRamp(t *testing.T) {
+	moves := []Move{{Name: "cruza", Prob: 0.3}}
+
+	if got := applyDifficultyRamp(moves, GlobalOptions{}, 1); got[0].Prob != 0.3 {
+		t.Errorf("round 1 não deveria alterar Prob, veio %v", got[0].Prob)
+	}
+
+	got := applyDifficultyRamp(moves, GlobalOptions{}, 3)
+	want := 0.3 * (1 - 2*0.1)
+	if got[0].Prob != want {
+		t.Errorf("round 3: esperava Prob %v, veio %v", want, got[0].Prob)
+	}
+
+	got = applyDifficultyRamp([]Move{{Name: "tip", Prob: 0.05}}, GlobalOptions{}, 20)
+	if got[0].Prob < 0 {
+		t.Errorf("Prob não deveria ficar negativa, veio %v", got[0].Prob)
+	}
+}
+
+func TestNextMoveErrorsWhenNoMovesAllowed(t *testing.T) {
+	// jab e direto são golpes de braço (Leg: false); exigir perna deixa
+	// o conjunto permitido vazio, reproduzindo `-level 1 -ol`.
+	moves := []Move{{Name: "jab", Front: true}, {Name: "direto", Back: true}}
+
+	_, err := NextMove(moves, RulesOptions{
+		IsFront:    true,
+		ExtraRules: []Rule{MustBeLegRule},
+	})
+	if err == nil {
+		t.Fatal("esperava erro quando nenhum movimento passa nas regras, veio nil")
+	}
+}
+
+func TestNextMoveReturnsAllowedMove(t *testing.T) {
+	moves := []Move{{Name: "jab", Front: true}, {Name: "direto", Back: true}}
+
+	m, err := NextMove(moves, RulesOptions{IsFront: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Name != "jab" {
+		t.Errorf("IsFront=true só deveria permitir jab, veio %q", m.Name)
+	}
+}
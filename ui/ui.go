@@ -0,0 +1,199 @@
+// Package ui é um front-end em modo texto para o training.Session,
+// mostrando o cronômetro do round, a próxima série e o histórico de
+// séries já tocadas, e permitindo pausar, pular, trocar o intervalo e
+// alternar onlyArm/onlyLeg em tempo real.
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/igoracmelo/manopla-go/training"
+)
+
+const historySize = 8
+
+// UI conduz um training.Session através de uma tela tcell.
+type UI struct {
+	screen  tcell.Screen
+	session *training.Session
+	control *training.Control
+
+	// interval é a cópia da UI do intervalo atual entre séries. Uma vez
+	// que o Session começa a rodar, Global.Interval só é lido/escrito
+	// por ele mesmo (em wait()); a UI nunca toca em u.session.Global
+	// depois disso, só manda o novo valor por Control.SetInterval.
+	interval time.Duration
+
+	current training.PhaseEvent
+	history []string
+}
+
+// New cria a tela e inicializa o Control do Session, mas não começa a
+// rodar o treino ainda — isso só acontece em Run.
+func New(session *training.Session) (*UI, error) {
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return nil, err
+	}
+	if err := screen.Init(); err != nil {
+		return nil, err
+	}
+
+	u := &UI{
+		screen:   screen,
+		session:  session,
+		control:  training.NewControl(),
+		interval: session.Global.Interval,
+	}
+
+	session.Control = u.control
+
+	// preserva hooks já ligados por quem criou o Session (ex: um
+	// session.Recorder), só acrescentando o comportamento da UI.
+	prevOnPhase := session.OnPhase
+	session.OnPhase = func(e training.PhaseEvent) {
+		if prevOnPhase != nil {
+			prevOnPhase(e)
+		}
+		u.onPhase(e)
+	}
+
+	prevOnCombo := session.OnCombo
+	session.OnCombo = func(moves []training.Move) {
+		if prevOnCombo != nil {
+			prevOnCombo(moves)
+		}
+		u.onCombo(moves)
+	}
+
+	return u, nil
+}
+
+// Run substitui o antigo "aperte uma tecla para sair": o Session roda
+// em uma goroutine enquanto esta conduz o loop de eventos do teclado.
+func (u *UI) Run() error {
+	defer u.screen.Fini()
+
+	quit := make(chan struct{})
+	u.session.Quit = quit
+
+	done := make(chan error, 1)
+	go func() {
+		done <- u.session.Run()
+	}()
+
+	events := make(chan tcell.Event)
+	go u.screen.ChannelEvents(events, quit)
+
+	for {
+		select {
+		case err := <-done:
+			return err
+
+		case ev := <-events:
+			switch ev := ev.(type) {
+			case *tcell.EventKey:
+				if u.handleKey(ev) {
+					close(quit)
+					return <-done
+				}
+			case *tcell.EventResize:
+				u.screen.Sync()
+			}
+		}
+	}
+}
+
+// handleKey trata uma tecla e retorna true se o treino deve encerrar.
+func (u *UI) handleKey(ev *tcell.EventKey) bool {
+	switch ev.Key() {
+	case tcell.KeyEscape, tcell.KeyCtrlC:
+		return true
+	case tcell.KeyRune:
+		switch ev.Rune() {
+		case 'q':
+			return true
+		case 'p':
+			send(u.control.TogglePause)
+		case 's':
+			send(u.control.Skip)
+		case 'a':
+			send(u.control.ToggleOnlyArm)
+		case 'l':
+			send(u.control.ToggleOnlyLeg)
+		case '+':
+			u.interval += 100 * time.Millisecond
+			sendInterval(u.control.SetInterval, u.interval)
+		case '-':
+			if iv := u.interval - 100*time.Millisecond; iv > 0 {
+				u.interval = iv
+				sendInterval(u.control.SetInterval, iv)
+			}
+		}
+	}
+	return false
+}
+
+func (u *UI) onPhase(e training.PhaseEvent) {
+	u.current = e
+	u.draw()
+}
+
+func (u *UI) onCombo(moves []training.Move) {
+	names := make([]string, len(moves))
+	for i, m := range moves {
+		names[i] = m.Name
+	}
+	combo := strings.Join(names, " ")
+
+	u.history = append(u.history, combo)
+	if len(u.history) > historySize {
+		u.history = u.history[len(u.history)-historySize:]
+	}
+
+	u.draw()
+}
+
+func (u *UI) draw() {
+	u.screen.Clear()
+
+	status := fmt.Sprintf("[%s] round %d — faltam %s", u.current.Phase, u.current.Round, u.current.Remaining.Round(time.Second))
+	drawText(u.screen, 0, 0, status)
+
+	if len(u.history) > 0 {
+		drawText(u.screen, 0, 2, "próxima: "+u.history[len(u.history)-1])
+	}
+
+	drawText(u.screen, 0, 4, "histórico:")
+	for i, combo := range u.history {
+		drawText(u.screen, 2, 5+i, combo)
+	}
+
+	help := "p: pausar/retomar   s: pular   a: só braço   l: só perna   +/-: intervalo   q/esc: sair"
+	drawText(u.screen, 0, 5+historySize+1, help)
+
+	u.screen.Show()
+}
+
+func drawText(s tcell.Screen, x, y int, text string) {
+	for i, r := range text {
+		s.SetContent(x+i, y, r, nil, tcell.StyleDefault)
+	}
+}
+
+func send(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+func sendInterval(ch chan time.Duration, d time.Duration) {
+	select {
+	case ch <- d:
+	default:
+	}
+}